@@ -19,26 +19,110 @@ import (
 	"github.com/fossas/fossa-cli/pkg"
 )
 
-func Graph(dir string) (graph.Deps, error) {
-	jarFilePaths, err := doublestar.Glob(filepath.Join(dir, "*.jar"))
+// Options controls the behavior of Graph. The zero value is the default,
+// network-enabled behavior.
+type Options struct {
+	// NoNetwork disables any analysis step that would otherwise reach out to
+	// a remote Maven repository (e.g. resolving a jar's GAV by SHA1 against
+	// Maven Central). Resolution falls back to whatever can be determined
+	// from the jar's own contents and, failing that, its filename.
+	NoNetwork bool
+
+	// MaxNestedDepth bounds how many levels of fat-jar nesting (e.g. a jar
+	// under BOOT-INF/lib/ of another jar) are traversed. Zero means
+	// DefaultMaxNestedDepth.
+	MaxNestedDepth int
+	// MaxUncompressedSize guards against zip bombs when extracting nested
+	// archives. Zero means DefaultMaxUncompressedSize.
+	MaxUncompressedSize int64
+
+	// NoLicenseClassification disables running discovered license text
+	// through the SPDX classifier, leaving raw declared licenses (from
+	// pom.xml or META-INF) unclassified.
+	NoLicenseClassification bool
+}
+
+func (o Options) maxNestedDepth() int {
+	if o.MaxNestedDepth > 0 {
+		return o.MaxNestedDepth
+	}
+	return DefaultMaxNestedDepth
+}
+
+func (o Options) maxUncompressedSize() int64 {
+	if o.MaxUncompressedSize > 0 {
+		return o.MaxUncompressedSize
+	}
+	return DefaultMaxUncompressedSize
+}
+
+// centralCacheDir returns the directory used to cache Maven Central SHA1
+// lookups across runs. An empty string disables caching but not lookups.
+func centralCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "fossa-cli", "maven-central")
+}
+
+// discoverJars returns the jars that actually participate in the build in
+// dir: when a build.xml is present and declares usable classpaths, those
+// concrete entries (each <fileset> is expanded by its includes pattern only;
+// its excludes attribute is not read); when it isn't, or resolves to no jars,
+// every jar found under dir.
+func discoverJars(dir string) ([]string, error) {
+	build, err := ParseBuildFile(filepath.Join(dir, "build.xml"))
+	if err != nil {
+		log.Debugf("no usable build.xml in %s, falling back to directory scan: %s", dir, err)
+		return doublestar.Glob(filepath.Join(dir, "**/*.jar"))
+	}
+
+	seen := make(map[string]bool)
+	var entries []string
+	for _, target := range build.Targets {
+		classpathEntries, err := build.ClasspathEntries(dir, target.Name)
+		if err != nil {
+			log.Debugf("could not resolve classpath for build.xml target %q: %s", target.Name, err)
+			continue
+		}
+		for _, entry := range classpathEntries {
+			if strings.HasSuffix(entry, ".jar") && !seen[entry] {
+				seen[entry] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		log.Debugf("build.xml in %s declared no jar classpath entries, falling back to directory scan", dir)
+		return doublestar.Glob(filepath.Join(dir, "**/*.jar"))
+	}
+	return entries, nil
+}
+
+func Graph(dir string, opts Options) (graph.Deps, error) {
+	jarFilePaths, err := discoverJars(dir)
 	if err != nil {
 		return graph.Deps{}, err
 	}
 
 	log.Debugf("Running Ant analysis: %#v", jarFilePaths)
 
-	// traverse through libdir and and resolve jars
+	central := maven.NewCentralClient(centralCacheDir(), opts.NoNetwork)
+	resolver := maven.NewResolver(opts.NoNetwork)
+
+	// traverse through libdir and and resolve jars, recursing into any
+	// nested jar/war/ear archives (e.g. Spring Boot fat jars) each one
+	// contains.
 	var imports []pkg.Import
 	depGraph := make(map[pkg.ID]pkg.Package)
 	for _, jarFilePath := range jarFilePaths {
-		locator, err := locatorFromJar(jarFilePath)
+		locator, err := processJar(jarFilePath, central, resolver, depGraph, 0, opts)
 		if err == nil {
 			imports = append(imports, pkg.Import{
 				Resolved: locator,
 			})
-			depGraph[locator] = pkg.Package{
-				ID: locator,
-			}
 		} else {
 			log.Warnf("unable to resolve Jar: %s", jarFilePath)
 		}
@@ -50,8 +134,93 @@ func Graph(dir string) (graph.Deps, error) {
 	}, nil
 }
 
-// locatorFromJar resolves a locator from a .jar file by inspecting its contents.
-func locatorFromJar(path string) (pkg.ID, error) {
+// processJar resolves jarPath's own locator, then recurses (up to
+// opts.maxNestedDepth()) into any nested jar/war/ear archives it contains.
+// The jar and each nested archive it resolves are added to depGraph, with
+// the containing jar's entry importing each nested archive it directly
+// embeds.
+func processJar(jarPath string, central maven.Client, resolver *maven.Resolver, depGraph map[pkg.ID]pkg.Package, depth int, opts Options) (pkg.ID, error) {
+	locator, manifest, err := locatorFromJar(jarPath, central)
+	if err != nil {
+		return pkg.ID{}, err
+	}
+
+	var resolved *maven.Manifest
+	if manifest != nil {
+		r := resolver.Resolve(*manifest)
+		resolved = &r
+	}
+
+	childImports := importsFromManifest(resolved, depGraph)
+	licenses := discoverLicenses(jarPath, resolved, opts)
+
+	if depth < opts.maxNestedDepth() {
+		nestedPaths, err := extractNestedArchives(jarPath, opts.maxUncompressedSize())
+		if err != nil {
+			log.Debugf("could not scan %s for nested archives: %s", jarPath, err)
+		}
+		for _, nestedPath := range nestedPaths {
+			nestedLocator, err := processJar(nestedPath, central, resolver, depGraph, depth+1, opts)
+			if err == nil {
+				childImports = append(childImports, pkg.Import{Resolved: nestedLocator})
+			} else {
+				log.Warnf("unable to resolve nested Jar in %s: %s", jarPath, nestedPath)
+			}
+			os.Remove(nestedPath)
+		}
+	}
+
+	if existing, ok := depGraph[locator]; ok {
+		existing.Imports = append(existing.Imports, childImports...)
+		if existing.Licenses == nil {
+			existing.Licenses = licenses
+		}
+		depGraph[locator] = existing
+	} else {
+		depGraph[locator] = pkg.Package{
+			ID:       locator,
+			Imports:  childImports,
+			Licenses: licenses,
+		}
+	}
+
+	return locator, nil
+}
+
+// importsFromManifest returns one pkg.Import per compile/runtime dependency
+// resolved declares, registering each as a (possibly incomplete) leaf in
+// depGraph if it isn't already present. resolved is nil for jars that
+// weren't resolved from an embedded pom.xml, in which case there's nothing
+// to walk and importsFromManifest returns nil.
+func importsFromManifest(resolved *maven.Manifest, depGraph map[pkg.ID]pkg.Package) []pkg.Import {
+	if resolved == nil {
+		return nil
+	}
+
+	var imports []pkg.Import
+	for _, dep := range resolved.Dependencies {
+		if dep.Scope != "" && dep.Scope != "compile" && dep.Scope != "runtime" {
+			continue
+		}
+
+		id := pkg.ID{
+			Type:     pkg.Maven,
+			Name:     dep.GroupID + ":" + dep.ArtifactID,
+			Revision: dep.Version,
+		}
+		imports = append(imports, pkg.Import{Resolved: id})
+		if _, ok := depGraph[id]; !ok {
+			depGraph[id] = pkg.Package{ID: id}
+		}
+	}
+	return imports
+}
+
+// locatorFromJar resolves a locator from a .jar file by inspecting its
+// contents. When the locator is resolved from an embedded pom.xml, the
+// parsed (unresolved) Manifest is also returned so callers can walk its
+// <parent> chain for transitive dependencies; it is nil in all other cases.
+func locatorFromJar(path string, central maven.Client) (pkg.ID, *maven.Manifest, error) {
 	log.Debugf("processing locator from Jar: %s", path)
 
 	info, err := jargo.GetJarInfo(path)
@@ -64,14 +233,14 @@ func locatorFromJar(path string) (pkg.ID, error) {
 			}
 		}
 
-		pomFile, err := getPOMFromJar(pomFilePath)
+		pomFile, err := getPOMFromJar(path, pomFilePath)
 		if err == nil {
 			log.Debugf("resolving locator from pom: %s", pomFilePath)
 			return pkg.ID{
 				Type:     pkg.Maven,
 				Name:     pomFile.GroupID + ":" + pomFile.ArtifactID,
 				Revision: pomFile.Version,
-			}, nil
+			}, &pomFile, nil
 		} else {
 			log.Debugf("%s", err)
 		}
@@ -84,7 +253,26 @@ func locatorFromJar(path string) (pkg.ID, error) {
 				Type:     pkg.Maven,
 				Name:     manifest["Bundle-SymbolicName"], // TODO: identify GroupId
 				Revision: manifest["Implementation-Version"],
-			}, nil
+			}, nil, nil
+		}
+	}
+
+	// many jars shipped in Ant lib/ dirs (old commons-*, hand-built jars)
+	// carry neither an embedded pom.xml nor useful OSGi manifest headers, but
+	// are still well-known Maven Central artifacts. Identify them by the
+	// SHA1 of their contents before giving up and parsing the filename.
+	if central != nil {
+		if sha1sum, err := maven.SHA1File(path); err == nil {
+			if gav, err := central.ResolveSHA1(sha1sum); err == nil {
+				log.Debugf("resolving locator from Maven Central sha1 match: %s", path)
+				return pkg.ID{
+					Type:     pkg.Maven,
+					Name:     gav.GroupID + ":" + gav.ArtifactID,
+					Revision: gav.Version,
+				}, nil, nil
+			}
+		} else {
+			log.Debugf("could not hash jar for Maven Central lookup: %s", err)
 		}
 	}
 
@@ -104,25 +292,27 @@ func locatorFromJar(path string) (pkg.ID, error) {
 	}
 
 	if parsedProjectName == "" {
-		return pkg.ID{}, errors.New("unable to parse jar file")
+		return pkg.ID{}, nil, errors.New("unable to parse jar file")
 	}
 
 	return pkg.ID{
 		Type:     pkg.Maven,
 		Name:     parsedProjectName,
 		Revision: parsedRevisionName,
-	}, nil
+	}, nil, nil
 }
 
-func getPOMFromJar(path string) (maven.Manifest, error) {
+// getPOMFromJar decodes the pom.xml at pomEntry (an intra-jar path, e.g.
+// "META-INF/maven/g/a/pom.xml") out of the jar file at jarPath.
+func getPOMFromJar(jarPath string, pomEntry string) (maven.Manifest, error) {
 	var pomFile maven.Manifest
 
-	log.Debugf(path)
-	if path == "" {
+	log.Debugf(pomEntry)
+	if pomEntry == "" {
 		return pomFile, errors.New("invalid POM path specified")
 	}
 
-	jarFile, err := os.Open(path)
+	jarFile, err := os.Open(jarPath)
 	if err != nil {
 		return pomFile, err
 	}
@@ -141,7 +331,7 @@ func getPOMFromJar(path string) (maven.Manifest, error) {
 
 	for _, f := range zr.File {
 		// decode a single pom.xml directly from jar
-		if f.Name == path {
+		if f.Name == pomEntry {
 			rc, err := f.Open()
 			if err != nil {
 				return pomFile, err
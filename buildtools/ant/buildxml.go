@@ -0,0 +1,245 @@
+package ant
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/bmatcuk/doublestar"
+)
+
+// BuildFile is the subset of an Ant build.xml needed to resolve the concrete
+// classpath a given target actually builds with, rather than assuming every
+// jar sitting next to the buildfile participates.
+type BuildFile struct {
+	Properties []BuildProperty `xml:"property"`
+	Paths      []Path          `xml:"path"`
+	Targets    []Target        `xml:"target"`
+}
+
+// BuildProperty is a top-level <property name="..." value="..."/> or
+// <property name="..." file="build.properties"/> declaration.
+type BuildProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	File  string `xml:"file,attr"`
+}
+
+// Path is a named <path id="..."> classpath definition.
+type Path struct {
+	ID           string        `xml:"id,attr"`
+	PathElements []PathElement `xml:"pathelement"`
+	FileSets     []FileSet     `xml:"fileset"`
+}
+
+// PathElement is a <pathelement location="..."/> entry inside a <path>.
+type PathElement struct {
+	Location string `xml:"location,attr"`
+}
+
+// FileSet is a <fileset dir="..." includes="..."/> entry inside a <path>.
+type FileSet struct {
+	Dir      string `xml:"dir,attr"`
+	Includes string `xml:"includes,attr"`
+}
+
+// Target is a top-level Ant <target>. A target doesn't reference a named
+// <path> itself; one of the tasks it runs does, either via a classpathref
+// attribute directly on the task (e.g. <javac classpathref="..."/>) or a
+// <classpath refid="..."/> nested arbitrarily deep inside it (e.g.
+// <junit><classpath refid="..."/></junit>). Task is deliberately untyped so
+// that any task element, at any nesting depth, is captured.
+type Target struct {
+	Name  string `xml:"name,attr"`
+	Tasks []Task `xml:",any"`
+}
+
+// Task is a single element nested (to any depth) inside a <target>, e.g.
+// <javac>, <junit>, or <classpath> itself. Ant defines dozens of task types
+// with arbitrary nesting, so rather than modeling each one, Task captures
+// just the attributes needed to find a classpath reference wherever it
+// appears: a classpathref attribute on the task itself, or a refid attribute
+// if the task is a <classpath> element.
+type Task struct {
+	XMLName      xml.Name
+	ClasspathRef string `xml:"classpathref,attr"`
+	RefID        string `xml:"refid,attr"`
+	Tasks        []Task `xml:",any"`
+}
+
+// classpathRefIDs returns every named <path> id that t's tasks reference,
+// however deeply nested, via either a classpathref attribute or a nested
+// <classpath refid="..."/>.
+func (t Target) classpathRefIDs() []string {
+	var refIDs []string
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.ClasspathRef != "" {
+				refIDs = append(refIDs, task.ClasspathRef)
+			}
+			if task.XMLName.Local == "classpath" && task.RefID != "" {
+				refIDs = append(refIDs, task.RefID)
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(t.Tasks)
+	return refIDs
+}
+
+// ParseBuildFile reads and parses the build.xml at path.
+func ParseBuildFile(path string) (BuildFile, error) {
+	var build BuildFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return build, err
+	}
+	if err := xml.Unmarshal(data, &build); err != nil {
+		return build, err
+	}
+	return build, nil
+}
+
+// ResolveProperties resolves build's own <property> declarations into a flat
+// name -> value map, loading any file="..."-style imports (e.g.
+// build.properties) relative to baseDir. Ant resolves properties in
+// declaration order and a property, once set, can't be overridden, so
+// earlier declarations win.
+func (b BuildFile) ResolveProperties(baseDir string) map[string]string {
+	props := map[string]string{}
+	for _, p := range b.Properties {
+		if p.File != "" {
+			imported, err := loadPropertiesFile(filepath.Join(baseDir, p.File))
+			if err != nil {
+				log.Debugf("could not load imported properties file %s: %s", p.File, err)
+				continue
+			}
+			for k, v := range imported {
+				if _, ok := props[k]; !ok {
+					props[k] = v
+				}
+			}
+			continue
+		}
+		if _, ok := props[p.Name]; !ok {
+			props[p.Name] = p.Value
+		}
+	}
+	return props
+}
+
+// loadPropertiesFile parses a Java-style key=value properties file.
+func loadPropertiesFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	props := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		props[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return props, nil
+}
+
+var propertyReferencePattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// resolveProperties interpolates ${...} placeholders in s against props,
+// leaving unresolved placeholders as-is, matching Ant's own behavior for an
+// undefined property.
+func resolveProperties(s string, props map[string]string) string {
+	return propertyReferencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		key := match[2 : len(match)-1]
+		if v, ok := props[key]; ok {
+			return v
+		}
+		return match
+	})
+}
+
+// ClasspathEntries resolves the concrete, on-disk jar paths that target
+// actually builds with: it follows every classpathref attribute and nested
+// <classpath refid="..."/> found anywhere under target's tasks into the
+// matching named <path>, expands each <pathelement location="..."/> and
+// <fileset dir="..." includes="..."/> relative to baseDir, and interpolates
+// ${property} references along the way.
+func (b BuildFile) ClasspathEntries(baseDir string, target string) ([]string, error) {
+	props := b.ResolveProperties(baseDir)
+
+	pathsByID := make(map[string]Path, len(b.Paths))
+	for _, p := range b.Paths {
+		pathsByID[p.ID] = p
+	}
+
+	var refIDs []string
+	for _, t := range b.Targets {
+		if t.Name != target {
+			continue
+		}
+		for _, refID := range t.classpathRefIDs() {
+			refIDs = append(refIDs, resolveProperties(refID, props))
+		}
+	}
+
+	seen := make(map[string]bool)
+	var entries []string
+	for _, refID := range refIDs {
+		path, ok := pathsByID[refID]
+		if !ok {
+			log.Warnf("build.xml target %q references undefined path %q", target, refID)
+			continue
+		}
+		for _, entry := range resolvePathEntries(path, baseDir, props) {
+			if !seen[entry] {
+				seen[entry] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func resolvePathEntries(path Path, baseDir string, props map[string]string) []string {
+	var entries []string
+
+	for _, pe := range path.PathElements {
+		location := resolveProperties(pe.Location, props)
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(baseDir, location)
+		}
+		entries = append(entries, location)
+	}
+
+	for _, fs := range path.FileSets {
+		dir := resolveProperties(fs.Dir, props)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+
+		includes := resolveProperties(fs.Includes, props)
+		if includes == "" {
+			includes = "*.jar"
+		}
+
+		matches, err := doublestar.Glob(filepath.Join(dir, includes))
+		if err != nil {
+			log.Debugf("could not glob fileset %s/%s: %s", dir, includes, err)
+			continue
+		}
+		entries = append(entries, matches...)
+	}
+
+	return entries
+}
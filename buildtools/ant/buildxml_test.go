@@ -0,0 +1,176 @@
+package ant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// sampleBuildXML mirrors how a real build.xml expresses a target's
+// classpath: never as a bare <classpath> child of <target>, but either as a
+// classpathref attribute on the task that needs it (<javac>), or a
+// <classpath refid="..."/> nested inside a task that supports a richer
+// classpath (<junit>), possibly several levels deep under other nested
+// elements (<batchtest> inside <junit>).
+const sampleBuildXML = `<?xml version="1.0"?>
+<project name="sample" default="compile">
+  <property file="build.properties"/>
+  <property name="build.dir" value="build"/>
+  <property name="build.dir" value="should-not-override"/>
+
+  <path id="compile.classpath">
+    <pathelement location="${lib.dir}/core.jar"/>
+    <fileset dir="${lib.dir}/ext" includes="*.jar"/>
+  </path>
+  <path id="test.classpath">
+    <pathelement location="${lib.dir}/test-support.jar"/>
+  </path>
+
+  <target name="compile">
+    <javac srcdir="src" destdir="${build.dir}" classpathref="compile.classpath"/>
+  </target>
+  <target name="test">
+    <junit>
+      <batchtest>
+        <classpath refid="test.classpath"/>
+      </batchtest>
+    </junit>
+  </target>
+  <target name="missing">
+    <javac srcdir="src" classpathref="missing.path"/>
+  </target>
+</project>
+`
+
+const sampleBuildProperties = `
+# comment lines and blanks are ignored
+
+lib.dir=lib
+`
+
+func writeSampleBuild(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "build.xml"), []byte(sampleBuildXML), 0644); err != nil {
+		t.Fatalf("could not write build.xml fixture: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "build.properties"), []byte(sampleBuildProperties), 0644); err != nil {
+		t.Fatalf("could not write build.properties fixture: %s", err)
+	}
+
+	extDir := filepath.Join(dir, "lib", "ext")
+	if err := os.MkdirAll(extDir, 0755); err != nil {
+		t.Fatalf("could not create fixture ext dir: %s", err)
+	}
+	for _, name := range []string{"a.jar", "b.jar"} {
+		if err := os.WriteFile(filepath.Join(extDir, name), []byte("jar"), 0644); err != nil {
+			t.Fatalf("could not write fixture jar %s: %s", name, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0755); err != nil {
+		t.Fatalf("could not create fixture lib dir: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib", "core.jar"), []byte("jar"), 0644); err != nil {
+		t.Fatalf("could not write fixture jar core.jar: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib", "test-support.jar"), []byte("jar"), 0644); err != nil {
+		t.Fatalf("could not write fixture jar test-support.jar: %s", err)
+	}
+
+	return dir
+}
+
+// TestResolveProperties asserts that an imported build.properties file and
+// inline <property> declarations are merged, with earlier declarations
+// winning, matching Ant's own "first write wins" semantics.
+func TestResolveProperties(t *testing.T) {
+	dir := writeSampleBuild(t)
+
+	build, err := ParseBuildFile(filepath.Join(dir, "build.xml"))
+	if err != nil {
+		t.Fatalf("ParseBuildFile returned error: %s", err)
+	}
+
+	props := build.ResolveProperties(dir)
+	if got, want := props["lib.dir"], "lib"; got != want {
+		t.Fatalf("props[lib.dir] = %q, want %q", got, want)
+	}
+	if got, want := props["build.dir"], "build"; got != want {
+		t.Fatalf("props[build.dir] = %q, want %q (first declaration should win)", got, want)
+	}
+}
+
+// TestClasspathEntries asserts that a classpathref attribute on a task
+// (<javac classpathref="..."/>) resolves through the named <path> into
+// concrete, on-disk jar paths, with ${property} placeholders interpolated
+// and filesets expanded.
+func TestClasspathEntries(t *testing.T) {
+	dir := writeSampleBuild(t)
+
+	build, err := ParseBuildFile(filepath.Join(dir, "build.xml"))
+	if err != nil {
+		t.Fatalf("ParseBuildFile returned error: %s", err)
+	}
+
+	entries, err := build.ClasspathEntries(dir, "compile")
+	if err != nil {
+		t.Fatalf("ClasspathEntries returned error: %s", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "lib", "core.jar"),
+		filepath.Join(dir, "lib", "ext", "a.jar"),
+		filepath.Join(dir, "lib", "ext", "b.jar"),
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("ClasspathEntries = %v, want %v", entries, want)
+	}
+	for i, w := range want {
+		if entries[i] != w {
+			t.Fatalf("ClasspathEntries[%d] = %q, want %q", i, entries[i], w)
+		}
+	}
+}
+
+// TestClasspathEntries_NestedClasspath asserts that a <classpath
+// refid="..."/> nested arbitrarily deep inside a task (e.g.
+// <junit><batchtest><classpath .../></batchtest></junit>) is still found,
+// not just one that's a direct child of <target>.
+func TestClasspathEntries_NestedClasspath(t *testing.T) {
+	dir := writeSampleBuild(t)
+
+	build, err := ParseBuildFile(filepath.Join(dir, "build.xml"))
+	if err != nil {
+		t.Fatalf("ParseBuildFile returned error: %s", err)
+	}
+
+	entries, err := build.ClasspathEntries(dir, "test")
+	if err != nil {
+		t.Fatalf("ClasspathEntries returned error: %s", err)
+	}
+
+	want := []string{filepath.Join(dir, "lib", "test-support.jar")}
+	if len(entries) != len(want) || entries[0] != want[0] {
+		t.Fatalf("ClasspathEntries = %v, want %v", entries, want)
+	}
+}
+
+// TestClasspathEntries_UndefinedPath asserts that a target referencing a
+// <path> that doesn't exist yields no entries rather than an error, since
+// other targets in the same build.xml may still resolve fine.
+func TestClasspathEntries_UndefinedPath(t *testing.T) {
+	dir := writeSampleBuild(t)
+
+	build, err := ParseBuildFile(filepath.Join(dir, "build.xml"))
+	if err != nil {
+		t.Fatalf("ParseBuildFile returned error: %s", err)
+	}
+
+	entries, err := build.ClasspathEntries(dir, "missing")
+	if err != nil {
+		t.Fatalf("ClasspathEntries returned error: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("ClasspathEntries = %v, want none", entries)
+	}
+}
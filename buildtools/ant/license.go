@@ -0,0 +1,125 @@
+package ant
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/licensecheck"
+
+	"github.com/fossas/fossa-cli/buildtools/maven"
+	"github.com/fossas/fossa-cli/pkg"
+)
+
+// MaxLicenseFileSize caps how much of a candidate license file is read, to
+// avoid pathological archives (e.g. a "LICENSE.txt" that's actually a
+// multi-gigabyte data file).
+const MaxLicenseFileSize = 1 << 20 // 1 MiB
+
+// licenseCandidatePaths are checked, in order, for raw license text when a
+// jar's pom.xml carries no <licenses> declaration.
+var licenseCandidatePaths = []string{
+	"META-INF/LICENSE",
+	"META-INF/LICENSE.txt",
+	"LICENSE",
+	"LICENSE.txt",
+	"META-INF/NOTICE",
+}
+
+// discoverLicenses resolves licenses for the jar at jarPath: it prefers
+// <licenses> declared in manifest (which may already reflect a parent pom,
+// once resolved through maven.Resolver), and otherwise scans the jar for
+// well-known META-INF license files. Raw license text is run through an
+// SPDX classifier to yield a usable license type, unless opts disables it.
+func discoverLicenses(jarPath string, manifest *maven.Manifest, opts Options) []pkg.License {
+	if manifest != nil && len(manifest.Licenses) > 0 {
+		licenses := make([]pkg.License, 0, len(manifest.Licenses))
+		for _, l := range manifest.Licenses {
+			licenses = append(licenses, pkg.License{Type: l.Name})
+		}
+		return licenses
+	}
+
+	contents, name := readFirstLicenseFile(jarPath)
+	if contents == nil {
+		return nil
+	}
+
+	license := pkg.License{Type: name, Contents: string(contents)}
+	if !opts.NoLicenseClassification {
+		if spdxID := classifyLicense(contents); spdxID != "" {
+			license.Type = spdxID
+		}
+	}
+	return []pkg.License{license}
+}
+
+// readFirstLicenseFile returns the contents (capped at MaxLicenseFileSize)
+// and base name of the first candidate license file found in jarPath, in
+// licenseCandidatePaths order, falling back to any file under
+// META-INF/licenses/.
+func readFirstLicenseFile(jarPath string) ([]byte, string) {
+	f, err := os.Open(jarPath)
+	if err != nil {
+		return nil, ""
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, ""
+	}
+
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return nil, ""
+	}
+
+	byName := make(map[string]*zip.File, len(zr.File))
+	var extras []*zip.File
+	for _, entry := range zr.File {
+		byName[entry.Name] = entry
+		if strings.HasPrefix(entry.Name, "META-INF/licenses/") {
+			extras = append(extras, entry)
+		}
+	}
+
+	var entry *zip.File
+	for _, candidate := range licenseCandidatePaths {
+		if match, ok := byName[candidate]; ok {
+			entry = match
+			break
+		}
+	}
+	if entry == nil && len(extras) > 0 {
+		entry = extras[0]
+	}
+	if entry == nil {
+		return nil, ""
+	}
+
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, ""
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MaxLicenseFileSize))
+	if err != nil || len(data) == 0 {
+		return nil, ""
+	}
+	return data, filepath.Base(entry.Name)
+}
+
+// classifyLicense runs contents through google/licensecheck's n-gram
+// similarity matcher and returns its best guess at an SPDX ID, or "" if no
+// confident match is found.
+func classifyLicense(contents []byte) string {
+	cov, err := licensecheck.Scan(contents)
+	if err != nil || len(cov.Match) == 0 {
+		return ""
+	}
+	return cov.Match[0].ID
+}
@@ -0,0 +1,107 @@
+package ant
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+)
+
+const (
+	// DefaultMaxNestedDepth bounds how many levels of fat-jar nesting
+	// (a jar inside a jar inside a jar...) are traversed.
+	DefaultMaxNestedDepth = 2
+	// DefaultMaxUncompressedSize guards against zip bombs: a nested archive
+	// entry larger than this, uncompressed, is skipped rather than extracted.
+	DefaultMaxUncompressedSize = 1 << 30 // 1 GiB
+)
+
+// nestedArchiveExtensions lists the extensions of jar entries that are
+// themselves archives worth recursing into: Spring Boot's BOOT-INF/lib/*.jar, servlet containers'
+// WEB-INF/lib/*.jar, shaded jars with nested jars at the root, and nested
+// war/ear assemblies.
+var nestedArchiveExtensions = []string{".jar", ".war", ".ear"}
+
+func isNestedArchive(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, candidate := range nestedArchiveExtensions {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// extractNestedArchives scans jarPath for entries that look like nested
+// jar/war/ear archives (e.g. under BOOT-INF/lib/ or WEB-INF/lib/) and
+// extracts each to a temp file, bounded by maxUncompressedSize to guard
+// against zip bombs. Callers are responsible for removing the returned
+// paths once done with them.
+func extractNestedArchives(jarPath string, maxUncompressedSize int64) ([]string, error) {
+	f, err := os.Open(jarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	var extracted []string
+	for _, entry := range zr.File {
+		if !isNestedArchive(entry.Name) {
+			continue
+		}
+		if int64(entry.UncompressedSize64) > maxUncompressedSize {
+			log.Warnf("skipping nested archive %s in %s: exceeds max uncompressed size", entry.Name, jarPath)
+			continue
+		}
+
+		path, err := extractEntryToTemp(entry, maxUncompressedSize)
+		if err != nil {
+			log.Debugf("could not extract nested archive %s from %s: %s", entry.Name, jarPath, err)
+			continue
+		}
+		extracted = append(extracted, path)
+	}
+	return extracted, nil
+}
+
+func extractEntryToTemp(entry *zip.File, maxUncompressedSize int64) (string, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "fossa-nested-*"+filepath.Ext(entry.Name))
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	// Guard against a lying uncompressed-size header by refusing to write
+	// past the limit we already checked the header against.
+	written, err := io.Copy(tmp, io.LimitReader(rc, maxUncompressedSize+1))
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if written > maxUncompressedSize {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("nested archive %s exceeds max uncompressed size", entry.Name)
+	}
+
+	return tmp.Name(), nil
+}
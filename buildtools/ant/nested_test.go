@@ -0,0 +1,106 @@
+package ant
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestZip(t *testing.T, path string, entries map[string][]byte) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("could not create fixture zip %s: %s", path, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, contents := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("could not add entry %s: %s", name, err)
+		}
+		if _, err := w.Write(contents); err != nil {
+			t.Fatalf("could not write entry %s: %s", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("could not finalize fixture zip: %s", err)
+	}
+}
+
+// TestExtractNestedArchives_FindsKnownExtensions asserts that nested
+// jar/war/ear entries are extracted but non-archive entries are left alone.
+func TestExtractNestedArchives_FindsKnownExtensions(t *testing.T) {
+	dir := t.TempDir()
+	jarPath := filepath.Join(dir, "app.jar")
+	writeTestZip(t, jarPath, map[string][]byte{
+		"BOOT-INF/lib/dep-a.jar":      []byte("jar a"),
+		"BOOT-INF/lib/dep-b.war":      []byte("war b"),
+		"BOOT-INF/classes/Main.class": []byte("class"),
+	})
+
+	extracted, err := extractNestedArchives(jarPath, DefaultMaxUncompressedSize)
+	if err != nil {
+		t.Fatalf("extractNestedArchives returned error: %s", err)
+	}
+	defer func() {
+		for _, p := range extracted {
+			os.Remove(p)
+		}
+	}()
+
+	if len(extracted) != 2 {
+		t.Fatalf("extractNestedArchives returned %d entries, want 2: %v", len(extracted), extracted)
+	}
+	for _, p := range extracted {
+		if _, err := os.Stat(p); err != nil {
+			t.Fatalf("extracted archive %s not on disk: %s", p, err)
+		}
+	}
+}
+
+// TestExtractNestedArchives_SkipsOversizedEntries asserts that an entry
+// declaring an uncompressed size over the limit is skipped rather than
+// extracted, guarding against zip bombs.
+func TestExtractNestedArchives_SkipsOversizedEntries(t *testing.T) {
+	dir := t.TempDir()
+	jarPath := filepath.Join(dir, "app.jar")
+	writeTestZip(t, jarPath, map[string][]byte{
+		"BOOT-INF/lib/huge.jar": []byte("0123456789"),
+	})
+
+	extracted, err := extractNestedArchives(jarPath, 5)
+	if err != nil {
+		t.Fatalf("extractNestedArchives returned error: %s", err)
+	}
+	defer func() {
+		for _, p := range extracted {
+			os.Remove(p)
+		}
+	}()
+
+	if len(extracted) != 0 {
+		t.Fatalf("extractNestedArchives = %v, want none (entry exceeds max size)", extracted)
+	}
+}
+
+// TestIsNestedArchive asserts that only the well-known fat-jar extensions are
+// recursed into, case-insensitively.
+func TestIsNestedArchive(t *testing.T) {
+	cases := map[string]bool{
+		"BOOT-INF/lib/dep.jar":       true,
+		"WEB-INF/lib/dep.JAR":        true,
+		"nested.war":                 true,
+		"assembly.ear":               true,
+		"BOOT-INF/classes/App.class": false,
+		"README.md":                  false,
+	}
+	for name, want := range cases {
+		if got := isNestedArchive(name); got != want {
+			t.Errorf("isNestedArchive(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package maven
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/pkg/errors"
+)
+
+// CentralSearchURL is the Maven Central Solr search endpoint used to resolve
+// a jar's GAV coordinates from the SHA1 checksum of its contents.
+const CentralSearchURL = "https://search.maven.org/solrsearch/select"
+
+// GAV identifies a Maven artifact by its groupId, artifactId, and version.
+type GAV struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+}
+
+// Client resolves Maven coordinates for jars that don't carry their own
+// pom.xml or OSGi manifest metadata.
+type Client interface {
+	// ResolveSHA1 looks up the GAV for a jar given the SHA1 checksum of its
+	// bytes. It returns an error if no artifact could be found.
+	ResolveSHA1(sha1sum string) (GAV, error)
+}
+
+// CentralClient queries the Maven Central REST search API and caches results
+// on disk, keyed by SHA1, so that repeated analyses of the same jars don't
+// repeat the network round-trip.
+type CentralClient struct {
+	HTTPClient *http.Client
+	CacheDir   string
+	NoNetwork  bool
+
+	// SearchURL overrides CentralSearchURL. Exposed for tests; production
+	// callers should leave this unset.
+	SearchURL string
+}
+
+// NewCentralClient constructs a CentralClient. cacheDir may be empty, in
+// which case lookups are never cached. When noNetwork is true, ResolveSHA1
+// only ever consults the cache.
+func NewCentralClient(cacheDir string, noNetwork bool) *CentralClient {
+	return &CentralClient{
+		HTTPClient: &http.Client{Timeout: 20 * time.Second},
+		CacheDir:   cacheDir,
+		NoNetwork:  noNetwork,
+		SearchURL:  CentralSearchURL,
+	}
+}
+
+func (c *CentralClient) searchURL() string {
+	if c.SearchURL != "" {
+		return c.SearchURL
+	}
+	return CentralSearchURL
+}
+
+type solrSearchResponse struct {
+	Response struct {
+		Docs []struct {
+			GroupID    string `json:"g"`
+			ArtifactID string `json:"a"`
+			Version    string `json:"v"`
+		} `json:"docs"`
+	} `json:"response"`
+}
+
+// ResolveSHA1 implements Client.
+func (c *CentralClient) ResolveSHA1(sha1sum string) (GAV, error) {
+	if gav, ok := c.readCache(sha1sum); ok {
+		return gav, nil
+	}
+
+	if c.NoNetwork {
+		return GAV{}, errors.New("network disabled: cannot resolve jar against Maven Central")
+	}
+
+	query := url.Values{}
+	query.Set("q", "1:"+sha1sum)
+	query.Set("rows", "1")
+	query.Set("wt", "json")
+
+	res, err := c.HTTPClient.Get(c.searchURL() + "?" + query.Encode())
+	if err != nil {
+		return GAV{}, errors.Wrap(err, "could not query Maven Central")
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return GAV{}, errors.Wrap(err, "could not read Maven Central response")
+	}
+
+	var parsed solrSearchResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return GAV{}, errors.Wrap(err, "could not parse Maven Central response")
+	}
+
+	if len(parsed.Response.Docs) == 0 {
+		return GAV{}, errors.Errorf("no Maven Central match for sha1 %q", sha1sum)
+	}
+
+	doc := parsed.Response.Docs[0]
+	gav := GAV{GroupID: doc.GroupID, ArtifactID: doc.ArtifactID, Version: doc.Version}
+	c.writeCache(sha1sum, gav)
+	return gav, nil
+}
+
+func (c *CentralClient) cachePath(sha1sum string) string {
+	return filepath.Join(c.CacheDir, sha1sum+".json")
+}
+
+func (c *CentralClient) readCache(sha1sum string) (GAV, bool) {
+	if c.CacheDir == "" {
+		return GAV{}, false
+	}
+	data, err := ioutil.ReadFile(c.cachePath(sha1sum))
+	if err != nil {
+		return GAV{}, false
+	}
+	var gav GAV
+	if err := json.Unmarshal(data, &gav); err != nil {
+		return GAV{}, false
+	}
+	return gav, true
+}
+
+func (c *CentralClient) writeCache(sha1sum string, gav GAV) {
+	if c.CacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.CacheDir, 0755); err != nil {
+		log.Debugf("could not create Maven Central cache dir: %s", err)
+		return
+	}
+	data, err := json.Marshal(gav)
+	if err != nil {
+		log.Debugf("could not marshal Maven Central cache entry: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(c.cachePath(sha1sum), data, 0644); err != nil {
+		log.Debugf("could not write Maven Central cache entry: %s", err)
+	}
+}
+
+// SHA1File computes the SHA1 checksum of a file's contents, for use as a
+// Maven Central lookup key.
+func SHA1File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
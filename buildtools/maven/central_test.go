@@ -0,0 +1,92 @@
+package maven
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCentralClient_ResolveSHA1_CacheFirst asserts that a cached result short
+// circuits the lookup: the Maven Central endpoint must never be hit.
+func TestCentralClient_ResolveSHA1_CacheFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("cached sha1 should not reach the network")
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	client := &CentralClient{HTTPClient: server.Client(), CacheDir: cacheDir, SearchURL: server.URL}
+
+	want := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0.0"}
+	client.writeCache("deadbeef", want)
+
+	got, err := client.ResolveSHA1("deadbeef")
+	if err != nil {
+		t.Fatalf("ResolveSHA1 returned error: %s", err)
+	}
+	if got != want {
+		t.Fatalf("ResolveSHA1 = %+v, want %+v", got, want)
+	}
+}
+
+// TestCentralClient_ResolveSHA1_NetworkFallback asserts that a cache miss
+// falls back to querying the search endpoint, and that the resolved GAV is
+// then written back to the cache.
+func TestCentralClient_ResolveSHA1_NetworkFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"response":{"docs":[{"g":"com.example","a":"widget","v":"1.0.0"}]}}`)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	client := &CentralClient{HTTPClient: server.Client(), CacheDir: cacheDir, SearchURL: server.URL}
+
+	got, err := client.ResolveSHA1("deadbeef")
+	if err != nil {
+		t.Fatalf("ResolveSHA1 returned error: %s", err)
+	}
+	want := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0.0"}
+	if got != want {
+		t.Fatalf("ResolveSHA1 = %+v, want %+v", got, want)
+	}
+
+	if _, ok := client.readCache("deadbeef"); !ok {
+		t.Fatal("expected ResolveSHA1 to populate the cache after a network hit")
+	}
+}
+
+// TestCentralClient_ResolveSHA1_NoNetwork asserts that a cache miss with
+// NoNetwork set returns an error instead of reaching out to Maven Central.
+func TestCentralClient_ResolveSHA1_NoNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("NoNetwork should prevent any request from being made")
+	}))
+	defer server.Close()
+
+	client := &CentralClient{HTTPClient: server.Client(), NoNetwork: true, SearchURL: server.URL}
+
+	if _, err := client.ResolveSHA1("deadbeef"); err == nil {
+		t.Fatal("expected ResolveSHA1 to fail with NoNetwork and no cache entry")
+	}
+}
+
+// TestSHA1File asserts that SHA1File hashes a file's contents rather than its
+// name or metadata.
+func TestSHA1File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "widget-1.0.0.jar")
+	if err := os.WriteFile(path, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	sum, err := SHA1File(path)
+	if err != nil {
+		t.Fatalf("SHA1File returned error: %s", err)
+	}
+	if want := "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"; sum != want {
+		t.Fatalf("SHA1File = %s, want %s", sum, want)
+	}
+}
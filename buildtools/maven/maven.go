@@ -0,0 +1,90 @@
+// Package maven provides shared helpers for resolving Maven coordinates
+// (group, artifact, version) and POM metadata on behalf of the Ant, Gradle,
+// and Maven analyzers, which all end up needing to make sense of jars and
+// pom.xml files pulled from a local repository or build output directory.
+package maven
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Manifest represents the subset of a Maven pom.xml that the analyzers in
+// this repo care about when identifying a jar and its dependencies.
+type Manifest struct {
+	XMLName    xml.Name `xml:"project"`
+	GroupID    string   `xml:"groupId"`
+	ArtifactID string   `xml:"artifactId"`
+	Version    string   `xml:"version"`
+
+	Parent     Parent     `xml:"parent"`
+	Properties Properties `xml:"properties"`
+
+	Dependencies         []Dependency `xml:"dependencies>dependency"`
+	DependencyManagement struct {
+		Dependencies []Dependency `xml:"dependencies>dependency"`
+	} `xml:"dependencyManagement"`
+
+	Licenses []License `xml:"licenses>license"`
+}
+
+// License is a single entry from a pom.xml's <licenses> block.
+type License struct {
+	Name string `xml:"name"`
+	URL  string `xml:"url"`
+}
+
+// Parent identifies the pom.xml that a Manifest inherits groupId, version,
+// properties, dependencyManagement, and dependencies from.
+type Parent struct {
+	GroupID      string `xml:"groupId"`
+	ArtifactID   string `xml:"artifactId"`
+	Version      string `xml:"version"`
+	RelativePath string `xml:"relativePath"`
+}
+
+// Dependency is a single entry from a pom.xml's <dependencies> or
+// <dependencyManagement><dependencies> block.
+type Dependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+// Properties represents a pom.xml <properties> block. Maven properties are
+// declared as arbitrary element names (e.g. <revision>1.2.3</revision>
+// defines a property named "revision"), so this can't be modeled as a
+// struct and instead implements xml.Unmarshaler directly.
+type Properties map[string]string
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	props := Properties{}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &el); err != nil {
+				return err
+			}
+			props[el.Name.Local] = value
+		case xml.EndElement:
+			if el == start.End() {
+				*p = props
+				return nil
+			}
+		}
+	}
+
+	*p = props
+	return nil
+}
@@ -0,0 +1,118 @@
+package maven
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/pkg/errors"
+)
+
+// snapshotSuffix marks a version as a Maven SNAPSHOT, whose actual artifact
+// filenames are timestamped rather than the literal coordinate.
+const snapshotSuffix = "-SNAPSHOT"
+
+// Metadata is the subset of a maven-metadata.xml document needed to resolve
+// a -SNAPSHOT coordinate to the actual timestamped filename a repository
+// serves it under (e.g. 1.2.3-20240617.101112-4).
+type Metadata struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Versioning struct {
+		SnapshotVersions []struct {
+			Extension string `xml:"extension"`
+			Value     string `xml:"value"`
+			Updated   string `xml:"updated"`
+		} `xml:"snapshotVersions>snapshotVersion"`
+	} `xml:"versioning"`
+}
+
+// snapshotVersion returns the timestamped <value> of the <snapshotVersion>
+// entry whose <extension> matches extension ("pom" or "jar"), or "" if none
+// is present.
+func (m Metadata) snapshotVersion(extension string) string {
+	for _, sv := range m.Versioning.SnapshotVersions {
+		if sv.Extension == extension {
+			return sv.Value
+		}
+	}
+	return ""
+}
+
+// snapshotArtifactFilename resolves the filename a repository actually
+// serves gav's artifact under. For non-snapshot versions this is just the
+// conventional <artifactId>-<version>.<extension> name. For -SNAPSHOT
+// versions it consults maven-metadata.xml and substitutes the timestamped
+// value for the given extension ("pom" or "jar"), falling back to the
+// literal -SNAPSHOT name if metadata is unavailable or doesn't cover it.
+func (r *Resolver) snapshotArtifactFilename(gav GAV, extension string) string {
+	literal := gav.ArtifactID + "-" + gav.Version + "." + extension
+	if !strings.HasSuffix(gav.Version, snapshotSuffix) {
+		return literal
+	}
+
+	metadata, err := r.fetchMetadata(gav)
+	if err != nil {
+		log.Debugf("could not fetch maven-metadata.xml for %s:%s:%s: %s", gav.GroupID, gav.ArtifactID, gav.Version, err)
+		return literal
+	}
+
+	if value := metadata.snapshotVersion(extension); value != "" {
+		return gav.ArtifactID + "-" + value + "." + extension
+	}
+	return literal
+}
+
+func (r *Resolver) fetchMetadata(gav GAV) (*Metadata, error) {
+	if cached, ok := r.metadataCache[gav]; ok {
+		return cached, nil
+	}
+
+	data, err := ioutil.ReadFile(localArtifactPath(r.LocalRepository, gav, "maven-metadata.xml"))
+	if err != nil {
+		if r.NoNetwork {
+			return nil, errors.Wrap(err, "maven-metadata.xml not found locally and network is disabled")
+		}
+		data, err = r.fetchRemoteMetadata(gav)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var metadata Metadata
+	if err := xml.Unmarshal(data, &metadata); err != nil {
+		return nil, errors.Wrap(err, "could not parse maven-metadata.xml")
+	}
+
+	r.metadataCache[gav] = &metadata
+	return &metadata, nil
+}
+
+func (r *Resolver) fetchRemoteMetadata(gav GAV) ([]byte, error) {
+	var lastErr error = errors.New("no remote Maven repositories configured")
+	for _, repo := range r.RemoteRepositories {
+		metadataURL := remoteArtifactURL(repo, gav, "maven-metadata.xml")
+
+		res, err := r.HTTPClient.Get(metadataURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := func() ([]byte, error) {
+			defer res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				return nil, errors.Errorf("%s returned status %d", metadataURL, res.StatusCode)
+			}
+			return ioutil.ReadAll(res.Body)
+		}()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return body, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,113 @@
+package maven
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestResolver() *Resolver {
+	return &Resolver{
+		MaxDepth:      DefaultMaxParentDepth,
+		HTTPClient:    http.DefaultClient,
+		pomCache:      make(map[GAV]*Manifest),
+		metadataCache: make(map[GAV]*Metadata),
+	}
+}
+
+// TestSnapshotArtifactFilename_NonSnapshot asserts that a non-snapshot
+// version is never substituted, regardless of repository configuration.
+func TestSnapshotArtifactFilename_NonSnapshot(t *testing.T) {
+	r := newTestResolver()
+	gav := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0.0"}
+
+	if got, want := r.snapshotArtifactFilename(gav, "pom"), "widget-1.0.0.pom"; got != want {
+		t.Fatalf("snapshotArtifactFilename = %q, want %q", got, want)
+	}
+}
+
+// TestSnapshotArtifactFilename_LocalMetadata asserts that a -SNAPSHOT
+// coordinate is resolved against a canned maven-metadata.xml already present
+// in the local repository, with no network access involved.
+func TestSnapshotArtifactFilename_LocalMetadata(t *testing.T) {
+	r := newTestResolver()
+	r.LocalRepository = "testdata/local-repo"
+	r.NoNetwork = true
+
+	gav := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0-SNAPSHOT"}
+
+	got := r.snapshotArtifactFilename(gav, "pom")
+	want := "widget-1.0-20240617.101112-4.pom"
+	if got != want {
+		t.Fatalf("snapshotArtifactFilename = %q, want %q", got, want)
+	}
+}
+
+// TestSnapshotArtifactFilename_RemoteMetadata asserts that, absent a local
+// maven-metadata.xml, a -SNAPSHOT coordinate falls back to fetching metadata
+// from RemoteRepositories.
+func TestSnapshotArtifactFilename_RemoteMetadata(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata/remote-repo")))
+	defer server.Close()
+
+	r := newTestResolver()
+	r.LocalRepository = t.TempDir()
+	r.RemoteRepositories = []RemoteRepository{{URL: server.URL}}
+
+	gav := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0-SNAPSHOT"}
+
+	got := r.snapshotArtifactFilename(gav, "pom")
+	want := "widget-1.0-20240701.090000-7.pom"
+	if got != want {
+		t.Fatalf("snapshotArtifactFilename = %q, want %q", got, want)
+	}
+}
+
+// TestSnapshotArtifactFilename_FallsBackToLiteral asserts that if metadata
+// can't be found anywhere, the literal -SNAPSHOT filename is used rather than
+// failing the lookup outright.
+func TestSnapshotArtifactFilename_FallsBackToLiteral(t *testing.T) {
+	r := newTestResolver()
+	r.LocalRepository = t.TempDir()
+	r.NoNetwork = true
+
+	gav := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0-SNAPSHOT"}
+
+	got := r.snapshotArtifactFilename(gav, "pom")
+	want := "widget-1.0-SNAPSHOT.pom"
+	if got != want {
+		t.Fatalf("snapshotArtifactFilename = %q, want %q", got, want)
+	}
+}
+
+// TestReadLocalPOM_UsesLiteralSnapshotFilename asserts that readLocalPOM
+// reads the POM cached under its literal -SNAPSHOT filename even when a local
+// maven-metadata.xml with snapshotVersions is present alongside it (e.g. left
+// over from an earlier remote fetch) — a local .m2 repository never caches
+// snapshot artifacts under the timestamped name.
+func TestReadLocalPOM_UsesLiteralSnapshotFilename(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Fatal("readLocalPOM should not make a network request")
+	}))
+	defer server.Close()
+
+	r := newTestResolver()
+	r.LocalRepository = "testdata/local-repo"
+	r.RemoteRepositories = []RemoteRepository{{URL: server.URL}}
+
+	gav := GAV{GroupID: "com.example", ArtifactID: "widget", Version: "1.0-SNAPSHOT"}
+
+	data, err := r.readLocalPOM(gav)
+	if err != nil {
+		t.Fatalf("readLocalPOM returned error: %s", err)
+	}
+
+	var manifest Manifest
+	if err := xml.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("could not parse resolved pom: %s", err)
+	}
+	if manifest.ArtifactID != "widget" {
+		t.Fatalf("manifest.ArtifactID = %q, want %q", manifest.ArtifactID, "widget")
+	}
+}
@@ -0,0 +1,320 @@
+package maven
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxParentDepth bounds how many <parent> links Resolver will follow
+// before giving up, guarding against cyclical or pathologically deep POM
+// hierarchies.
+const DefaultMaxParentDepth = 5
+
+// RemoteRepository is a Maven repository that Resolver may fetch POMs from
+// when a coordinate isn't available in the local repository.
+type RemoteRepository struct {
+	URL string
+}
+
+// DefaultRemoteRepositories are consulted, in order, when a POM can't be
+// found in the local repository and no repositories are configured via
+// settings.xml.
+var DefaultRemoteRepositories = []RemoteRepository{
+	{URL: "https://repo1.maven.org/maven2"},
+}
+
+// Resolver flattens a Manifest's <parent> chain: for each ancestor it checks
+// the local repository first, then falls back to RemoteRepositories, merging
+// inherited properties, dependencyManagement, and dependencies into the
+// child and interpolating ${...} placeholders along the way. The result is a
+// Manifest with real GAVs and a usable dependency list, rather than just the
+// top-level jar's own (possibly incomplete) coordinates.
+type Resolver struct {
+	// LocalRepository is checked before any RemoteRepository, typically
+	// $HOME/.m2/repository.
+	LocalRepository string
+	// RemoteRepositories are consulted, in order, if NoNetwork is false.
+	RemoteRepositories []RemoteRepository
+	// MaxDepth bounds how many parent links are followed.
+	MaxDepth int
+	// NoNetwork disables fetching from RemoteRepositories entirely.
+	NoNetwork bool
+
+	HTTPClient *http.Client
+
+	pomCache      map[GAV]*Manifest
+	metadataCache map[GAV]*Metadata
+}
+
+// NewResolver constructs a Resolver using FOSSA's conventional defaults: the
+// user's local .m2 repository, any repositories declared in
+// $HOME/.m2/settings.xml, Maven Central as a final fallback, and a max
+// parent depth of DefaultMaxParentDepth.
+func NewResolver(noNetwork bool) *Resolver {
+	home, _ := os.UserHomeDir()
+	repos := append(settingsRepositories(filepath.Join(home, ".m2", "settings.xml")), DefaultRemoteRepositories...)
+
+	return &Resolver{
+		LocalRepository:    filepath.Join(home, ".m2", "repository"),
+		RemoteRepositories: repos,
+		MaxDepth:           DefaultMaxParentDepth,
+		NoNetwork:          noNetwork,
+		HTTPClient:         &http.Client{Timeout: 20 * time.Second},
+		pomCache:           make(map[GAV]*Manifest),
+		metadataCache:      make(map[GAV]*Metadata),
+	}
+}
+
+// Resolve walks manifest's <parent> chain and returns a flattened Manifest
+// with inherited properties, dependencyManagement, and dependencies merged
+// in and all ${...} placeholders interpolated.
+func (r *Resolver) Resolve(manifest Manifest) Manifest {
+	chain := []Manifest{manifest}
+
+	current := manifest
+	for depth := 0; current.Parent.ArtifactID != "" && depth < r.MaxDepth; depth++ {
+		parentGAV := GAV{GroupID: current.Parent.GroupID, ArtifactID: current.Parent.ArtifactID, Version: current.Parent.Version}
+		parent, err := r.fetchPOM(parentGAV)
+		if err != nil {
+			log.Debugf("could not resolve parent pom %s:%s:%s: %s", parentGAV.GroupID, parentGAV.ArtifactID, parentGAV.Version, err)
+			break
+		}
+		chain = append(chain, *parent)
+		current = *parent
+	}
+
+	// Merge root-to-leaf so that the original (most specific) manifest's
+	// values win over anything inherited.
+	var merged Manifest
+	for i := len(chain) - 1; i >= 0; i-- {
+		merged = mergeManifest(merged, chain[i])
+	}
+
+	merged.interpolate()
+	merged.applyDependencyManagement()
+	return merged
+}
+
+func mergeManifest(parent, child Manifest) Manifest {
+	merged := child
+	if merged.GroupID == "" {
+		merged.GroupID = parent.GroupID
+	}
+	if merged.Version == "" {
+		merged.Version = parent.Version
+	}
+
+	props := Properties{}
+	for k, v := range parent.Properties {
+		props[k] = v
+	}
+	for k, v := range child.Properties {
+		props[k] = v
+	}
+	merged.Properties = props
+
+	merged.DependencyManagement.Dependencies = append(
+		append([]Dependency{}, parent.DependencyManagement.Dependencies...),
+		child.DependencyManagement.Dependencies...,
+	)
+	merged.Dependencies = mergeDependencies(parent.Dependencies, child.Dependencies)
+
+	if len(merged.Licenses) == 0 {
+		merged.Licenses = parent.Licenses
+	}
+
+	return merged
+}
+
+// mergeDependencies unions a parent's and child's <dependencies>, preserving
+// first-seen order and letting the child's declaration win on conflicts —
+// Maven children inherit a parent's dependency declarations alongside their
+// own.
+func mergeDependencies(parentDeps, childDeps []Dependency) []Dependency {
+	merged := make(map[string]Dependency, len(parentDeps)+len(childDeps))
+	var order []string
+
+	add := func(deps []Dependency) {
+		for _, d := range deps {
+			key := d.GroupID + ":" + d.ArtifactID
+			if _, ok := merged[key]; !ok {
+				order = append(order, key)
+			}
+			merged[key] = d
+		}
+	}
+	add(parentDeps)
+	add(childDeps)
+
+	result := make([]Dependency, len(order))
+	for i, key := range order {
+		result[i] = merged[key]
+	}
+	return result
+}
+
+var propertyPlaceholder = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// interpolate resolves ${...} placeholders in m's own version and its
+// dependencies' versions, checking built-in project properties before
+// falling back to m.Properties.
+func (m *Manifest) interpolate() {
+	lookup := func(key string) (string, bool) {
+		switch key {
+		case "project.version", "pom.version", "version":
+			return m.Version, m.Version != ""
+		case "project.groupId", "pom.groupId", "groupId":
+			return m.GroupID, m.GroupID != ""
+		case "project.artifactId", "pom.artifactId", "artifactId":
+			return m.ArtifactID, m.ArtifactID != ""
+		}
+		v, ok := m.Properties[key]
+		return v, ok
+	}
+
+	resolve := func(s string) string {
+		return propertyPlaceholder.ReplaceAllStringFunc(s, func(match string) string {
+			key := match[2 : len(match)-1]
+			if v, ok := lookup(key); ok {
+				return v
+			}
+			return match
+		})
+	}
+
+	m.Version = resolve(m.Version)
+	for i := range m.Dependencies {
+		m.Dependencies[i].Version = resolve(m.Dependencies[i].Version)
+	}
+	for i := range m.DependencyManagement.Dependencies {
+		m.DependencyManagement.Dependencies[i].Version = resolve(m.DependencyManagement.Dependencies[i].Version)
+	}
+}
+
+// applyDependencyManagement fills in versions for dependencies that declare
+// no version of their own, from the merged dependencyManagement block.
+func (m *Manifest) applyDependencyManagement() {
+	managed := make(map[string]string, len(m.DependencyManagement.Dependencies))
+	for _, d := range m.DependencyManagement.Dependencies {
+		managed[d.GroupID+":"+d.ArtifactID] = d.Version
+	}
+	for i, d := range m.Dependencies {
+		if d.Version == "" {
+			m.Dependencies[i].Version = managed[d.GroupID+":"+d.ArtifactID]
+		}
+	}
+}
+
+func (r *Resolver) fetchPOM(gav GAV) (*Manifest, error) {
+	if cached, ok := r.pomCache[gav]; ok {
+		return cached, nil
+	}
+
+	data, err := r.readLocalPOM(gav)
+	if err != nil {
+		if r.NoNetwork {
+			return nil, errors.Wrap(err, "pom not found in local repository and network is disabled")
+		}
+		data, err = r.fetchRemotePOM(gav)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var manifest Manifest
+	if err := xml.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrap(err, "could not parse pom.xml")
+	}
+
+	r.pomCache[gav] = &manifest
+	return &manifest, nil
+}
+
+func (r *Resolver) readLocalPOM(gav GAV) ([]byte, error) {
+	if r.LocalRepository == "" {
+		return nil, errors.New("no local Maven repository configured")
+	}
+	// Unlike a remote repository, a local .m2 cache always stores a snapshot
+	// POM under its literal -SNAPSHOT filename, never the timestamped name
+	// from maven-metadata.xml, so this must not call snapshotArtifactFilename
+	// (which would also trigger an unwanted remote metadata fetch).
+	filename := gav.ArtifactID + "-" + gav.Version + ".pom"
+	return ioutil.ReadFile(localArtifactPath(r.LocalRepository, gav, filename))
+}
+
+// localArtifactPath builds the conventional $repo/<g-slashes>/<a>/<v>/<filename> layout.
+func localArtifactPath(repo string, gav GAV, filename string) string {
+	return filepath.Join(repo, filepath.FromSlash(strings.ReplaceAll(gav.GroupID, ".", "/")), gav.ArtifactID, gav.Version, filename)
+}
+
+func (r *Resolver) fetchRemotePOM(gav GAV) ([]byte, error) {
+	var lastErr error = errors.New("no remote Maven repositories configured")
+	for _, repo := range r.RemoteRepositories {
+		pomURL := remoteArtifactURL(repo, gav, r.snapshotArtifactFilename(gav, "pom"))
+
+		res, err := r.HTTPClient.Get(pomURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := func() ([]byte, error) {
+			defer res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				return nil, errors.Errorf("%s returned status %d", pomURL, res.StatusCode)
+			}
+			return ioutil.ReadAll(res.Body)
+		}()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return body, nil
+	}
+	return nil, lastErr
+}
+
+func remoteArtifactURL(repo RemoteRepository, gav GAV, filename string) string {
+	return strings.TrimSuffix(repo.URL, "/") + "/" +
+		strings.ReplaceAll(gav.GroupID, ".", "/") + "/" + gav.ArtifactID + "/" + gav.Version + "/" + filename
+}
+
+type mavenSettings struct {
+	Profiles []struct {
+		Repositories []struct {
+			URL string `xml:"url"`
+		} `xml:"repositories>repository"`
+	} `xml:"profiles>profile"`
+}
+
+// settingsRepositories parses a Maven settings.xml for repository URLs
+// declared under <profiles><profile><repositories>. It returns nil if the
+// file doesn't exist or can't be parsed.
+func settingsRepositories(path string) []RemoteRepository {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var settings mavenSettings
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		log.Debugf("could not parse %s: %s", path, err)
+		return nil
+	}
+
+	var repos []RemoteRepository
+	for _, profile := range settings.Profiles {
+		for _, repo := range profile.Repositories {
+			repos = append(repos, RemoteRepository{URL: repo.URL})
+		}
+	}
+	return repos
+}
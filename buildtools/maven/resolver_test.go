@@ -0,0 +1,88 @@
+package maven
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResolver_Resolve_ParentChain asserts that Resolve walks a <parent>
+// chain, merges inherited properties/dependencyManagement/dependencies, and
+// interpolates ${...} placeholders using the merged property set — with the
+// leaf manifest's own values always winning over anything inherited.
+func TestResolver_Resolve_ParentChain(t *testing.T) {
+	data, err := os.ReadFile("testdata/local-repo/com/example/parent-chain-child/1.0/parent-chain-child-1.0.pom")
+	if err != nil {
+		t.Fatalf("could not read fixture pom: %s", err)
+	}
+	var child Manifest
+	if err := xml.Unmarshal(data, &child); err != nil {
+		t.Fatalf("could not parse fixture pom: %s", err)
+	}
+
+	r := newTestResolver()
+	r.LocalRepository = "testdata/local-repo"
+	r.NoNetwork = true
+
+	resolved := r.Resolve(child)
+
+	if got, want := resolved.GroupID, "com.example"; got != want {
+		t.Errorf("GroupID = %q, want %q (inherited from parent)", got, want)
+	}
+	if got, want := resolved.ArtifactID, "parent-chain-child"; got != want {
+		t.Errorf("ArtifactID = %q, want %q", got, want)
+	}
+	if got, want := resolved.Version, "1.5"; got != want {
+		t.Errorf("Version = %q, want %q (interpolated from own ${revision})", got, want)
+	}
+
+	deps := make(map[string]string, len(resolved.Dependencies))
+	for _, d := range resolved.Dependencies {
+		deps[d.GroupID+":"+d.ArtifactID] = d.Version
+	}
+	if got, want := deps["com.example:shared-util"], "2.0"; got != want {
+		t.Errorf("shared-util version = %q, want %q (inherited from parent)", got, want)
+	}
+	if got, want := deps["com.google.guava:guava"], "31.1-jre"; got != want {
+		t.Errorf("guava version = %q, want %q (filled from merged dependencyManagement, itself interpolated)", got, want)
+	}
+
+	if len(resolved.Licenses) != 1 || resolved.Licenses[0].Name != "Apache-2.0" {
+		t.Errorf("Licenses = %v, want inherited [Apache-2.0] (child declares none of its own)", resolved.Licenses)
+	}
+}
+
+// TestResolver_Resolve_MaxDepth asserts that Resolve gives up following
+// <parent> links past MaxDepth rather than looping indefinitely on a
+// pathological or cyclical chain.
+func TestResolver_Resolve_MaxDepth(t *testing.T) {
+	r := newTestResolver()
+	r.LocalRepository = t.TempDir()
+	r.NoNetwork = true
+	r.MaxDepth = 2
+
+	// a POM that names itself as its own parent: every parent lookup
+	// succeeds, so only MaxDepth stops Resolve from following the cycle.
+	pomDir := filepath.Join(r.LocalRepository, "com", "example", "cyclic", "1.0")
+	if err := os.MkdirAll(pomDir, 0755); err != nil {
+		t.Fatalf("could not create fixture pom dir: %s", err)
+	}
+	cyclicPOM := `<project><groupId>com.example</groupId><artifactId>cyclic</artifactId><version>1.0</version>` +
+		`<parent><groupId>com.example</groupId><artifactId>cyclic</artifactId><version>1.0</version></parent></project>`
+	if err := os.WriteFile(filepath.Join(pomDir, "cyclic-1.0.pom"), []byte(cyclicPOM), 0644); err != nil {
+		t.Fatalf("could not write fixture pom: %s", err)
+	}
+
+	manifest := Manifest{
+		GroupID:    "com.example",
+		ArtifactID: "leaf",
+		Version:    "1.0",
+		Parent:     Parent{GroupID: "com.example", ArtifactID: "cyclic", Version: "1.0"},
+	}
+
+	resolved := r.Resolve(manifest)
+	if got, want := resolved.ArtifactID, "leaf"; got != want {
+		t.Fatalf("ArtifactID = %q, want %q (should still resolve the leaf manifest itself)", got, want)
+	}
+}